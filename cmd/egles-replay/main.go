@@ -0,0 +1,142 @@
+// Command egles-replay reads a .gfxtrace captured by es2/trace and re-issues
+// its GL calls against a live ES2 context, so a rendering bug captured with
+// -trace on one machine can be reproduced on another.
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	_ "github.com/mortdeus/egles/egl/platform/x11"
+
+	"github.com/mortdeus/egles/egl"
+	"github.com/mortdeus/egles/egl/platform"
+	gl "github.com/mortdeus/egles/es2"
+	"github.com/mortdeus/egles/es2/trace"
+)
+
+func main() {
+	path := flag.String("trace", "", "path to the .gfxtrace file to replay")
+	flag.Parse()
+	if *path == "" {
+		log.Fatal("usage: egles-replay -trace file.gfxtrace")
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	var first trace.BackbufferInfo
+	if err := binary.Read(r, binary.LittleEndian, &first); err != nil {
+		log.Fatal(err)
+	}
+
+	b, err := platform.New("x11")
+	if err != nil {
+		log.Fatal(err)
+	}
+	win, err := b.NewWindow(int(first.Width), int(first.Height), "egles-replay")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := platform.InitEGL(win, configAttribsFor(first), contextAttribs); err != nil {
+		log.Fatal(err)
+	}
+
+	info := first
+	frame := 0
+	for {
+		log.Printf("frame %d: %dx%d", frame, info.Width, info.Height)
+		if err := replayFrame(r); err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+		platform.Swap(nil, info.PreserveBuffersOnSwap)
+		frame++
+
+		if err := binary.Read(r, binary.LittleEndian, &info); err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+	}
+}
+
+var contextAttribs = []int32{
+	egl.CONTEXT_CLIENT_VERSION, 2,
+	egl.NONE,
+}
+
+// configAttribsFor builds the eglChooseConfig attributes a frame's
+// BackbufferInfo was actually recorded against, so replay recreates the
+// same kind of surface instead of a hardcoded guess.
+func configAttribsFor(info trace.BackbufferInfo) []int32 {
+	redSize := byte(info.ColorFormat >> 24)
+	greenSize := byte(info.ColorFormat >> 16)
+	blueSize := byte(info.ColorFormat >> 8)
+	alphaSize := byte(info.ColorFormat)
+	return []int32{
+		egl.RED_SIZE, int32(redSize),
+		egl.GREEN_SIZE, int32(greenSize),
+		egl.BLUE_SIZE, int32(blueSize),
+		egl.ALPHA_SIZE, int32(alphaSize),
+		egl.DEPTH_SIZE, int32(info.DepthFormat),
+		egl.STENCIL_SIZE, int32(info.StencilFormat),
+		egl.SURFACE_TYPE, platform.SurfaceTypeBits(egl.WINDOW_BIT, info.PreserveBuffersOnSwap),
+		egl.RENDERABLE_TYPE, egl.OPENGL_ES2_BIT,
+		egl.NONE,
+	}
+}
+
+// replayFrame re-issues every atom recorded between a BeginFrame and its
+// matching EndFrame, stopping at the FuncEndFrame sentinel.
+func replayFrame(r *bufio.Reader) error {
+	for {
+		var id trace.FuncID
+		if err := binary.Read(r, binary.LittleEndian, &id); err != nil {
+			return err
+		}
+		var length uint32
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return err
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+		var ret int32
+		var glErr uint32
+		if err := binary.Read(r, binary.LittleEndian, &ret); err != nil {
+			return err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &glErr); err != nil {
+			return err
+		}
+		if id == trace.FuncEndFrame {
+			return nil
+		}
+		replayAtom(id, payload)
+	}
+}
+
+func replayAtom(id trace.FuncID, payload []byte) {
+	u32 := func(i int) uint32 { return binary.LittleEndian.Uint32(payload[i:]) }
+	switch id {
+	case trace.FuncViewport:
+		gl.Viewport(int(u32(0)), int(u32(4)), int(u32(8)), int(u32(12)))
+	case trace.FuncClear:
+		gl.Clear(u32(0))
+	case trace.FuncDrawArrays:
+		gl.DrawArrays(u32(0), int(u32(4)), int(u32(8)))
+	}
+}
@@ -0,0 +1,108 @@
+package platform
+
+import (
+	"fmt"
+
+	"github.com/mortdeus/egles/egl"
+)
+
+// NativeWindow is the platform-specific window handle returned by a
+// Backend, wrapped so callers don't need to import backend-specific types.
+type NativeWindow interface {
+	// NativeHandle returns the window handle as the EGL native window
+	// type expected by eglCreateWindowSurface.
+	NativeHandle() egl.NativeWindowType
+}
+
+// EventType identifies the kind of Event delivered by a Backend's
+// EventLoop.
+type EventType int
+
+const (
+	EventResize EventType = iota
+	EventClose
+)
+
+// Event is a windowing event delivered on the channel passed to
+// Backend.EventLoop.
+type Event struct {
+	Type          EventType
+	Width, Height int
+}
+
+// Backend abstracts the platform-specific windowing glue (Xorg, Wayland,
+// Windows, ...) needed to create an EGL native window and pump its event
+// loop, so the rest of egles doesn't need a build-tagged switch per
+// platform.
+type Backend interface {
+	// NewWindow creates a native window of the given size and title.
+	NewWindow(w, h int, title string) (NativeWindow, error)
+	// EventLoop pumps platform events onto events until Shutdown is
+	// called. Backends run it in its own goroutine.
+	EventLoop(events chan<- Event)
+	// Resize resizes the backend's window.
+	Resize(w, h int)
+	// Shutdown tears down the backend's window and event loop.
+	Shutdown()
+}
+
+var backends = map[string]func() Backend{}
+
+// Register makes a Backend factory available under name. Backend packages
+// call this from their own init(), so the set of available backends is
+// decided by which backend package the build tags pull in.
+func Register(name string, factory func() Backend) {
+	if factory == nil {
+		panic("platform: Register factory is nil")
+	}
+	if _, dup := backends[name]; dup {
+		panic("platform: Register called twice for backend " + name)
+	}
+	backends[name] = factory
+}
+
+// New returns a new instance of the backend registered under name, or an
+// error if no such backend was registered.
+func New(name string) (Backend, error) {
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("platform: no backend registered under %q", name)
+	}
+	return factory(), nil
+}
+
+// InitEGL performs the backend-independent half of bringing up EGL for win:
+// it gets the default display, initializes it, chooses a config, and
+// creates a window surface and context from it. The results are stored in
+// Display, Surface and Context.
+func InitEGL(win NativeWindow, configAttribs, contextAttribs []int32) error {
+	dpy := egl.GetDisplay(egl.DefaultDisplay)
+	if !egl.Initialize(dpy, nil, nil) {
+		return fmt.Errorf("platform: eglInitialize failed")
+	}
+
+	var config egl.Config
+	var numConfigs int32
+	if !egl.ChooseConfig(dpy, configAttribs, &config, 1, &numConfigs) || numConfigs == 0 {
+		return fmt.Errorf("platform: eglChooseConfig found no matching config")
+	}
+
+	surface := egl.CreateWindowSurface(dpy, config, win.NativeHandle(), nil)
+	context := egl.CreateContext(dpy, config, egl.NoContext, contextAttribs)
+	if !egl.MakeCurrent(dpy, surface, surface, context) {
+		return fmt.Errorf("platform: eglMakeCurrent failed")
+	}
+
+	Display, Surface, Context, Config = dpy, surface, context, config
+	DetectExtensions()
+	return nil
+}
+
+// ConfigAttrib queries attrib (an egl.*_SIZE constant) on Config, the
+// config InitEGL chose. Callers use this to find out what they actually
+// got instead of assuming ChooseConfig honored their request exactly.
+func ConfigAttrib(attrib int32) int32 {
+	var value int32
+	egl.GetConfigAttrib(Display, Config, attrib, &value)
+	return value
+}
@@ -0,0 +1,70 @@
+// Package platform defines the pluggable windowing Backend interface
+// (x11, wayland, ...), and holds the EGL display/surface/context state that
+// InitEGL creates together with the swap helpers built on top of it.
+package platform
+
+import (
+	"image"
+	"strings"
+
+	"github.com/mortdeus/egles/egl"
+)
+
+// Display, Surface, Context and Config hold the handles created by the
+// last call to InitEGL.
+var (
+	Display egl.Display
+	Surface egl.Surface
+	Context egl.Context
+	Config  egl.Config
+)
+
+// PreserveBackbuffer is EGL_SWAP_BEHAVIOR_PRESERVED_BIT. Callers OR this
+// into the EGL_SURFACE_TYPE config attribute they pass to InitEGL when they
+// want a back buffer that survives across swaps, which Swap requires in
+// order to present damage rects instead of the whole surface.
+const PreserveBackbuffer = egl.SWAP_BEHAVIOR_PRESERVED_BIT
+
+// SurfaceTypeBits ORs PreserveBackbuffer into base when preserve is set.
+// Callers use this while building the EGL_SURFACE_TYPE attribute that
+// InitEGL passes to eglChooseConfig.
+func SurfaceTypeBits(base int32, preserve bool) int32 {
+	if preserve {
+		return base | PreserveBackbuffer
+	}
+	return base
+}
+
+var damageExtSupported bool
+
+// DetectExtensions inspects Display's EGL extension string and caches
+// whether EGL_EXT_swap_buffers_with_damage is available. InitEGL calls this
+// once, after eglInitialize has populated Display.
+func DetectExtensions() {
+	ext := egl.QueryString(Display, egl.EXTENSIONS)
+	damageExtSupported = strings.Contains(ext, "EGL_EXT_swap_buffers_with_damage")
+}
+
+// Swap presents Surface on Display. When preserve is true (the back buffer
+// was created with PreserveBackbuffer) and the platform advertises
+// EGL_EXT_swap_buffers_with_damage, only damageRects are presented via
+// eglSwapBuffersWithDamageEXT; otherwise Swap falls back to a full
+// eglSwapBuffers, since damage rects are only meaningful against a
+// preserved buffer.
+func Swap(damageRects []image.Rectangle, preserve bool) {
+	if preserve && damageExtSupported && len(damageRects) > 0 {
+		egl.SwapBuffersWithDamageEXT(Display, Surface, damageRectsToInts(damageRects))
+		return
+	}
+	egl.SwapBuffers(Display, Surface)
+}
+
+// damageRectsToInts flattens rects into the x,y,width,height quads
+// eglSwapBuffersWithDamageEXT expects.
+func damageRectsToInts(rects []image.Rectangle) []int32 {
+	ints := make([]int32, 0, len(rects)*4)
+	for _, r := range rects {
+		ints = append(ints, int32(r.Min.X), int32(r.Min.Y), int32(r.Dx()), int32(r.Dy()))
+	}
+	return ints
+}
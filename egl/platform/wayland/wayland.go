@@ -0,0 +1,129 @@
+//go:build wayland
+// +build wayland
+
+// Package wayland is an egl/platform.Backend implementation backed by
+// Wayland, via cgo bindings to libwayland-client and libwayland-egl
+// (mirroring how Gio drives its own Wayland backend). It registers itself
+// under the name "wayland".
+//
+// The package is gated behind the wayland build tag, the same one
+// examples/es2/xorg/triangle/backend_wayland.go imports it under, so
+// go build ./... doesn't require wayland-client/wayland-egl dev headers
+// on machines that only want the x11 backend.
+package wayland
+
+/*
+#cgo pkg-config: wayland-client wayland-egl
+#include <stdlib.h>
+#include <wayland-client.h>
+#include <wayland-egl.h>
+
+extern void goRegistryGlobal(struct wl_registry *registry, uint32_t name,
+	const char *iface, uint32_t version);
+
+static void registry_global_trampoline(void *data, struct wl_registry *registry,
+	uint32_t name, const char *iface, uint32_t version) {
+	goRegistryGlobal(registry, name, iface, version);
+}
+
+static void registry_add_listener(struct wl_registry *registry) {
+	static const struct wl_registry_listener listener = {
+		.global = registry_global_trampoline,
+	};
+	wl_registry_add_listener(registry, &listener, NULL);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/mortdeus/egles/egl"
+	"github.com/mortdeus/egles/egl/platform"
+)
+
+func init() {
+	platform.Register("wayland", func() platform.Backend { return &backend{} })
+}
+
+// current is the in-flight backend being populated by registryGlobal. Only
+// one Wayland window is ever created per process, so a package-level
+// pointer is enough and keeps Go pointers out of the cgo callback.
+var current *backend
+
+// backend is the Wayland platform.Backend.
+type backend struct {
+	display    *C.struct_wl_display
+	compositor *C.struct_wl_compositor
+	shell      *C.struct_wl_shell
+	window     *window
+}
+
+// window is the platform.NativeWindow returned by backend.NewWindow.
+type window struct {
+	surface   *C.struct_wl_surface
+	eglWindow *C.struct_wl_egl_window
+}
+
+func (w *window) NativeHandle() egl.NativeWindowType {
+	return egl.NativeWindowType(uintptr(unsafe.Pointer(w.eglWindow)))
+}
+
+func (b *backend) NewWindow(w, h int, title string) (platform.NativeWindow, error) {
+	b.display = C.wl_display_connect(nil)
+	if b.display == nil {
+		return nil, fmt.Errorf("wayland: wl_display_connect failed")
+	}
+
+	registry := C.wl_display_get_registry(b.display)
+	current = b
+	C.registry_add_listener(registry)
+	C.wl_display_roundtrip(b.display)
+	current = nil
+	if b.compositor == nil || b.shell == nil {
+		return nil, fmt.Errorf("wayland: compositor or shell interface not advertised")
+	}
+
+	surface := C.wl_compositor_create_surface(b.compositor)
+	shellSurface := C.wl_shell_get_shell_surface(b.shell, surface)
+	C.wl_shell_surface_set_toplevel(shellSurface)
+	ctitle := C.CString(title)
+	defer C.free(unsafe.Pointer(ctitle))
+	C.wl_shell_surface_set_title(shellSurface, ctitle)
+
+	eglWindow := C.wl_egl_window_create(surface, C.int(w), C.int(h))
+	b.window = &window{surface: surface, eglWindow: eglWindow}
+	return b.window, nil
+}
+
+func (b *backend) EventLoop(events chan<- platform.Event) {
+	go func() {
+		for C.wl_display_dispatch(b.display) != -1 {
+		}
+		events <- platform.Event{Type: platform.EventClose}
+	}()
+}
+
+func (b *backend) Resize(w, h int) {
+	C.wl_egl_window_resize(b.window.eglWindow, C.int(w), C.int(h), 0, 0)
+}
+
+func (b *backend) Shutdown() {
+	C.wl_egl_window_destroy(b.window.eglWindow)
+	C.wl_surface_destroy(b.window.surface)
+	C.wl_display_disconnect(b.display)
+}
+
+//export goRegistryGlobal
+func goRegistryGlobal(registry *C.struct_wl_registry, name C.uint32_t, iface *C.char, version C.uint32_t) {
+	if current == nil {
+		return
+	}
+	switch C.GoString(iface) {
+	case "wl_compositor":
+		current.compositor = (*C.struct_wl_compositor)(C.wl_registry_bind(registry, name, &C.wl_compositor_interface, C.uint32_t(1)))
+	case "wl_shell":
+		current.shell = (*C.struct_wl_shell)(C.wl_registry_bind(registry, name, &C.wl_shell_interface, C.uint32_t(1)))
+	}
+}
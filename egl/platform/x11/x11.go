@@ -0,0 +1,82 @@
+// Package x11 is an egl/platform.Backend implementation backed by Xorg via
+// xgbutil. It registers itself under the name "x11".
+package x11
+
+import (
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/mousebind"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xwindow"
+	"github.com/mortdeus/egles/egl"
+	"github.com/mortdeus/egles/egl/platform"
+)
+
+func init() {
+	platform.Register("x11", func() platform.Backend { return &backend{} })
+}
+
+// backend is the Xorg platform.Backend.
+type backend struct {
+	conn   *xgbutil.XUtil
+	window *window
+}
+
+// window is the platform.NativeWindow returned by backend.NewWindow.
+type window struct {
+	xwin   *xwindow.Window
+	handle egl.NativeWindowType
+}
+
+func (w *window) NativeHandle() egl.NativeWindowType { return w.handle }
+
+func (b *backend) NewWindow(w, h int, title string) (platform.NativeWindow, error) {
+	conn, err := xgbutil.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	mousebind.Initialize(conn)
+
+	xwin, err := xwindow.Generate(conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := xwin.CreateChecked(conn.RootWin(), 0, 0, w, h, 0); err != nil {
+		return nil, err
+	}
+	ewmh.WmNameSet(conn, xwin.Id, title)
+	xwin.Map()
+
+	b.conn = conn
+	b.window = &window{
+		xwin:   xwin,
+		handle: egl.NativeWindowType(uintptr(xwin.Id)),
+	}
+	return b.window, nil
+}
+
+func (b *backend) EventLoop(events chan<- platform.Event) {
+	xevent.ConfigureNotifyFun(func(X *xgbutil.XUtil, e xevent.ConfigureNotifyEvent) {
+		events <- platform.Event{
+			Type:   platform.EventResize,
+			Width:  int(e.Width),
+			Height: int(e.Height),
+		}
+	}).Connect(b.conn, b.window.xwin.Id)
+
+	b.window.xwin.WMGracefulClose(func(w *xwindow.Window) {
+		events <- platform.Event{Type: platform.EventClose}
+		w.Destroy()
+	})
+
+	go xevent.Main(b.conn)
+}
+
+func (b *backend) Resize(w, h int) {
+	b.window.xwin.Resize(w, h)
+}
+
+func (b *backend) Shutdown() {
+	xevent.Quit(b.conn)
+	b.window.xwin.Destroy()
+}
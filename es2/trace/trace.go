@@ -0,0 +1,122 @@
+// Package trace records GL ES2 calls into a length-prefixed binary stream
+// (a ".gfxtrace") that cmd/egles-replay can later re-issue against a live
+// context, so a rendering bug can be captured on one machine and reproduced
+// on another.
+//
+// Only Viewport, Clear and DrawArrays are wrapped. Every other GL call is
+// not recorded: neither one-time setup such as linking a program or
+// uploading a buffer, nor per-frame calls such as BindBuffer or
+// VertexAttribPointer. Wrapping more entry points follows the same pattern
+// as traffic warrants it.
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	gl "github.com/mortdeus/egles/es2"
+)
+
+// FuncID identifies which GL call an atom recorded.
+type FuncID uint16
+
+const (
+	FuncViewport FuncID = iota
+	FuncClear
+	FuncDrawArrays
+	// FuncEndFrame is a sentinel atom with no payload, marking the end
+	// of the atoms recorded between a BeginFrame and the matching
+	// EndFrame call.
+	FuncEndFrame
+)
+
+// BackbufferInfo is written once at the start of every frame so a replay
+// can recreate the surface the frame was rendered against.
+type BackbufferInfo struct {
+	Width, Height                               int32
+	ColorFormat, DepthFormat, StencilFormat     uint32
+	ResetViewportScissor, PreserveBuffersOnSwap bool
+}
+
+// Recorder writes atoms to a .gfxtrace file.
+type Recorder struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// New creates path and returns a Recorder that appends atoms to it.
+func New(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Close flushes any buffered atoms and closes the underlying file.
+func (r *Recorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+// BeginFrame records info as the backbuffer header for the frame about to
+// be drawn.
+func (r *Recorder) BeginFrame(info BackbufferInfo) error {
+	return binary.Write(r.w, binary.LittleEndian, info)
+}
+
+// EndFrame writes the sentinel atom that tells a replay where this frame's
+// atoms stop and the next frame's BackbufferInfo header begins.
+func (r *Recorder) EndFrame() {
+	r.atom(FuncEndFrame, nil, 0)
+}
+
+// atom writes one length-prefixed record: the function id, the call's
+// argument payload, the GL return value and the GetError result observed
+// right after the call.
+func (r *Recorder) atom(id FuncID, payload []byte, ret int32) {
+	writeAtom(r.w, id, payload, ret, uint32(gl.GetError()))
+}
+
+// writeAtom encodes the wire format atom records share: the function id, a
+// uint32 payload length, the payload itself, the call's return value and
+// the GL error code observed after it. It's kept separate from atom so the
+// framing can be round-trip tested without a live GL context.
+func writeAtom(w io.Writer, id FuncID, payload []byte, ret int32, glErr uint32) {
+	binary.Write(w, binary.LittleEndian, id)
+	binary.Write(w, binary.LittleEndian, uint32(len(payload)))
+	w.Write(payload)
+	binary.Write(w, binary.LittleEndian, ret)
+	binary.Write(w, binary.LittleEndian, glErr)
+}
+
+func (r *Recorder) Viewport(x, y, width, height int) {
+	gl.Viewport(x, y, width, height)
+	payload := make([]byte, 16)
+	binary.LittleEndian.PutUint32(payload[0:], uint32(x))
+	binary.LittleEndian.PutUint32(payload[4:], uint32(y))
+	binary.LittleEndian.PutUint32(payload[8:], uint32(width))
+	binary.LittleEndian.PutUint32(payload[12:], uint32(height))
+	r.atom(FuncViewport, payload, 0)
+}
+
+func (r *Recorder) Clear(mask uint32) {
+	gl.Clear(mask)
+	payload := make([]byte, 4)
+	binary.LittleEndian.PutUint32(payload, mask)
+	r.atom(FuncClear, payload, 0)
+}
+
+func (r *Recorder) DrawArrays(mode uint32, first, count int) {
+	gl.DrawArrays(mode, first, count)
+	payload := make([]byte, 12)
+	binary.LittleEndian.PutUint32(payload[0:], mode)
+	binary.LittleEndian.PutUint32(payload[4:], uint32(first))
+	binary.LittleEndian.PutUint32(payload[8:], uint32(count))
+	r.atom(FuncDrawArrays, payload, 0)
+}
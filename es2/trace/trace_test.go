@@ -0,0 +1,89 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// TestBackbufferInfoRoundTrip exercises the exact struct layout BeginFrame
+// writes and cmd/egles-replay reads back as a frame's header.
+func TestBackbufferInfoRoundTrip(t *testing.T) {
+	want := BackbufferInfo{
+		Width:                 640,
+		Height:                480,
+		ColorFormat:           8<<24 | 8<<16 | 8<<8 | 8,
+		DepthFormat:           16,
+		StencilFormat:         0,
+		ResetViewportScissor:  true,
+		PreserveBuffersOnSwap: true,
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, want); err != nil {
+		t.Fatalf("binary.Write: %v", err)
+	}
+
+	var got BackbufferInfo
+	if err := binary.Read(&buf, binary.LittleEndian, &got); err != nil {
+		t.Fatalf("binary.Read: %v", err)
+	}
+	if got != want {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+// TestWriteAtomRoundTrip decodes an atom the same way cmd/egles-replay does
+// (FuncID, length, payload, ret, glErr) and checks it matches what was
+// encoded, including the zero-length payload EndFrame writes.
+func TestWriteAtomRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		id      FuncID
+		payload []byte
+		ret     int32
+		glErr   uint32
+	}{
+		{"viewport", FuncViewport, []byte{1, 2, 3, 4}, 0, 0},
+		{"endframe", FuncEndFrame, nil, 0, 0},
+		{"glerror", FuncClear, []byte{5, 6, 7, 8}, -1, 0x0500},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeAtom(&buf, c.id, c.payload, c.ret, c.glErr)
+
+			var gotID FuncID
+			if err := binary.Read(&buf, binary.LittleEndian, &gotID); err != nil {
+				t.Fatalf("read id: %v", err)
+			}
+			var length uint32
+			if err := binary.Read(&buf, binary.LittleEndian, &length); err != nil {
+				t.Fatalf("read length: %v", err)
+			}
+			payload := make([]byte, length)
+			if _, err := buf.Read(payload); length > 0 {
+				if err != nil {
+					t.Fatalf("read payload: %v", err)
+				}
+			}
+			var ret int32
+			if err := binary.Read(&buf, binary.LittleEndian, &ret); err != nil {
+				t.Fatalf("read ret: %v", err)
+			}
+			var glErr uint32
+			if err := binary.Read(&buf, binary.LittleEndian, &glErr); err != nil {
+				t.Fatalf("read glErr: %v", err)
+			}
+
+			if gotID != c.id || !bytes.Equal(payload, c.payload) || ret != c.ret || glErr != c.glErr {
+				t.Fatalf("round trip mismatch: got (id=%v payload=%v ret=%v glErr=%v), want (id=%v payload=%v ret=%v glErr=%v)",
+					gotID, payload, ret, glErr, c.id, c.payload, c.ret, c.glErr)
+			}
+			if buf.Len() != 0 {
+				t.Fatalf("%d trailing bytes after decode", buf.Len())
+			}
+		})
+	}
+}
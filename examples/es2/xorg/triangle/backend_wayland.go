@@ -0,0 +1,8 @@
+//go:build wayland
+// +build wayland
+
+package main
+
+import _ "github.com/mortdeus/egles/egl/platform/wayland"
+
+const backendName = "wayland"
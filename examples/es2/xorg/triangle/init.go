@@ -1,14 +1,11 @@
-// +build !raspberry
-
 package main
 
 import (
-	"github.com/BurntSushi/xgbutil"
-	"github.com/BurntSushi/xgbutil/mousebind"
-	"github.com/BurntSushi/xgbutil/xevent"
-	"github.com/mortdeus/egles/egl"
-	"github.com/mortdeus/egles/egl/platform/xorg"
 	"log"
+
+	"github.com/mortdeus/egles/egl"
+	"github.com/mortdeus/egles/egl/platform"
+	"github.com/mortdeus/egles/es2/trace"
 )
 
 const (
@@ -16,18 +13,64 @@ const (
 	INITIAL_WINDOW_HEIGHT = 480
 )
 
-var X *xgbutil.XUtil
+// configAttribs requests an ES2-capable RGB888 config (no alpha size is
+// requested) with a 16-bit depth
+// buffer and a preserved back buffer, since Run swaps damage rects rather
+// than the whole surface.
+var (
+	configAttribs = []int32{
+		egl.RED_SIZE, 8,
+		egl.GREEN_SIZE, 8,
+		egl.BLUE_SIZE, 8,
+		egl.DEPTH_SIZE, 16,
+		egl.SURFACE_TYPE, platform.SurfaceTypeBits(egl.WINDOW_BIT, true),
+		egl.RENDERABLE_TYPE, egl.OPENGL_ES2_BIT,
+		egl.NONE,
+	}
+	contextAttribs = []int32{
+		egl.CONTEXT_CLIENT_VERSION, 2,
+		egl.NONE,
+	}
+)
+
+// backend is the windowing backend selected at build time by
+// backend_*.go, and backendEvents carries its resize/close notifications
+// into renderLoop.Run.
+var (
+	backend       platform.Backend
+	backendEvents = make(chan platform.Event, 4)
+)
+
+// backbufferInfo describes the surface platform.InitEGL actually created,
+// queried back from the chosen EGL config rather than assumed from
+// configAttribs, so a trace's header matches what was really drawn into.
+// ColorFormat packs the RGBA bit sizes one byte each, most significant
+// first; DepthFormat and StencilFormat are the raw bit sizes.
+var backbufferInfo trace.BackbufferInfo
 
 func initialize() {
-	X, err := xgbutil.NewConn()
+	b, err := platform.New(backendName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	win, err := b.NewWindow(INITIAL_WINDOW_WIDTH, INITIAL_WINDOW_HEIGHT, "egles triangle")
 	if err != nil {
 		log.Fatal(err)
 	}
-	mousebind.Initialize(X)
-	xWindow := newWindow(X, INITIAL_WINDOW_WIDTH, INITIAL_WINDOW_HEIGHT)
-	go xevent.Main(X)
-	xorg.Initialize(
-		egl.NativeWindowType(uintptr(xWindow.Id)),
-		xorg.DefaultConfigAttributes,
-		xorg.DefaultContextAttributes)
+	if err := platform.InitEGL(win, configAttribs, contextAttribs); err != nil {
+		log.Fatal(err)
+	}
+	backend = b
+	backend.EventLoop(backendEvents)
+
+	backbufferInfo = trace.BackbufferInfo{
+		ColorFormat: uint32(platform.ConfigAttrib(egl.RED_SIZE))<<24 |
+			uint32(platform.ConfigAttrib(egl.GREEN_SIZE))<<16 |
+			uint32(platform.ConfigAttrib(egl.BLUE_SIZE))<<8 |
+			uint32(platform.ConfigAttrib(egl.ALPHA_SIZE)),
+		DepthFormat:           uint32(platform.ConfigAttrib(egl.DEPTH_SIZE)),
+		StencilFormat:         uint32(platform.ConfigAttrib(egl.STENCIL_SIZE)),
+		ResetViewportScissor:  true,
+		PreserveBuffersOnSwap: true,
+	}
 }
@@ -2,11 +2,14 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"github.com/mortdeus/application"
 	"github.com/mortdeus/egles/egl"
 	"github.com/mortdeus/egles/egl/platform"
 	gl "github.com/mortdeus/egles/es2"
+	"github.com/mortdeus/egles/es2/trace"
+	"github.com/mortdeus/egles/glutil"
+	"github.com/mortdeus/egles/shaderutil"
+	"image"
 	"log"
 	"os"
 	"runtime"
@@ -16,11 +19,21 @@ import (
 
 const FRAMES_PER_SECOND = 24
 
+// Vertex attribute locations, bound explicitly before linking so they
+// don't depend on driver-assigned locations.
+const (
+	attribPos   = 0
+	attribColor = 1
+)
+
+// tracer records every wrapped GL call to a .gfxtrace when -trace is set,
+// and is nil otherwise.
+var tracer *trace.Recorder
+
 var (
-	signal sigterm
-	verticesArrayBuffer, colorsArrayBuffer,
-	attrPos, attrColor uint
-	currWidth, currHeight int
+	signal                                 sigterm
+	verticesArrayBuffer, colorsArrayBuffer uint
+	currWidth, currHeight                  int
 
 	vertices = [12]float32{
 		-0.5, -0.5, 0.0, 1.0,
@@ -84,7 +97,9 @@ func (l *renderLoop) Run() {
 	runtime.LockOSThread()
 	initialize()
 	reshape(INITIAL_WINDOW_WIDTH, INITIAL_WINDOW_HEIGHT)
-	initShaders()
+	if err := initShaders(); err != nil {
+		log.Fatal(err)
+	}
 	for {
 		select {
 		case <-l.pause:
@@ -93,44 +108,96 @@ func (l *renderLoop) Run() {
 		case <-l.terminate:
 			cleanup()
 			l.terminate <- 0
+		case ev := <-backendEvents:
+			switch ev.Type {
+			case platform.EventResize:
+				reshape(ev.Width, ev.Height)
+			case platform.EventClose:
+				application.Exit()
+			}
 		case <-l.ticker.C:
+			if tracer != nil {
+				info := backbufferInfo
+				info.Width = int32(currWidth)
+				info.Height = int32(currHeight)
+				tracer.BeginFrame(info)
+			}
 			draw(currWidth, currHeight)
-			egl.SwapBuffers(platform.Display, platform.Surface)
+			if tracer != nil {
+				tracer.EndFrame()
+			}
+			platform.Swap([]image.Rectangle{triangleDamage(currWidth, currHeight)}, true)
 		}
 	}
 }
 
-func check() {
-	error := gl.GetError()
-	if error != 0 {
-		panic(fmt.Sprintf("An error occurred! Code: 0x%x", error))
+// viewport, clear and drawArrays route through tracer when tracing is
+// enabled so a .gfxtrace captures what the example actually drew each
+// frame. Every other GL call calls gl directly and is not traced: the
+// one-time setup made before the render loop's first BeginFrame, the
+// per-frame BindBuffer, VertexAttribPointer, Flush and Finish in draw, and
+// the Viewport in reshape.
+func viewport(x, y, w, h int) {
+	if tracer != nil {
+		tracer.Viewport(x, y, w, h)
+		return
 	}
+	gl.Viewport(x, y, w, h)
 }
 
-func initShaders() {
-	program := Program(FragmentShader(fsh), VertexShader(vsh))
+func clear(mask uint32) {
+	if tracer != nil {
+		tracer.Clear(mask)
+		return
+	}
+	gl.Clear(mask)
+}
+
+func drawArrays(mode uint32, first, count int) {
+	if tracer != nil {
+		tracer.DrawArrays(mode, first, count)
+		return
+	}
+	gl.DrawArrays(mode, first, count)
+}
+
+func initShaders() error {
+	fragShader, err := shaderutil.FragmentShader(fsh)
+	if err != nil {
+		return err
+	}
+	vertShader, err := shaderutil.VertexShader(vsh)
+	if err != nil {
+		return err
+	}
+	program, err := shaderutil.ProgramWithAttribs(fragShader, vertShader, map[uint32]string{
+		attribPos:   "pos",
+		attribColor: "color",
+	})
+	if err != nil {
+		return err
+	}
+	// initShaders runs once, before the render loop's first BeginFrame, so
+	// its calls go straight to gl rather than through the tracer wrappers
+	// above: a .gfxtrace must start with a BackbufferInfo header, not
+	// one-time setup atoms.
 	gl.UseProgram(program)
-	attrPos = uint(gl.GetAttribLocation(program, "pos"))
-	attrColor = uint(gl.GetAttribLocation(program, "color"))
-	gl.GenBuffers(1, gl.Void(&verticesArrayBuffer))
-	gl.BindBuffer(gl.ARRAY_BUFFER, verticesArrayBuffer)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Void(&vertices[0]), gl.STATIC_DRAW)
-	gl.GenBuffers(1, gl.Void(&colorsArrayBuffer))
-	gl.BindBuffer(gl.ARRAY_BUFFER, colorsArrayBuffer)
-	gl.BufferData(gl.ARRAY_BUFFER, len(colors)*4, gl.Void(&colors[0]), gl.STATIC_DRAW)
-	gl.EnableVertexAttribArray(attrPos)
-	gl.EnableVertexAttribArray(attrColor)
+	verticesArrayBuffer = glutil.CreateBuffer(gl.ARRAY_BUFFER, vertices[:], gl.STATIC_DRAW)
+	colorsArrayBuffer = glutil.CreateBuffer(gl.ARRAY_BUFFER, colors[:], gl.STATIC_DRAW)
+	gl.EnableVertexAttribArray(attribPos)
+	gl.EnableVertexAttribArray(attribColor)
 	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
+	return nil
 }
 
 func draw(width, height int) {
-	gl.Viewport(0, 0, width, height)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	viewport(0, 0, width, height)
+	clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.BindBuffer(gl.ARRAY_BUFFER, verticesArrayBuffer)
-	gl.VertexAttribPointer(attrPos, 4, gl.FLOAT, false, 0, gl.Void(uintptr(0)))
+	gl.VertexAttribPointer(attribPos, 4, gl.FLOAT, false, 0, gl.Void(uintptr(0)))
 	gl.BindBuffer(gl.ARRAY_BUFFER, colorsArrayBuffer)
-	gl.VertexAttribPointer(attrColor, 4, gl.FLOAT, false, 0, gl.Void(uintptr(0)))
-	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.VertexAttribPointer(attribColor, 4, gl.FLOAT, false, 0, gl.Void(uintptr(0)))
+	drawArrays(gl.TRIANGLES, 0, 3)
 	gl.Flush()
 	gl.Finish()
 }
@@ -139,6 +206,10 @@ func cleanup() {
 	egl.DestroySurface(platform.Display, platform.Surface)
 	egl.DestroyContext(platform.Display, platform.Context)
 	egl.Terminate(platform.Display)
+	backend.Shutdown()
+	if tracer != nil {
+		tracer.Close()
+	}
 }
 
 func reshape(width, height int) {
@@ -146,6 +217,19 @@ func reshape(width, height int) {
 	gl.Viewport(0, 0, width, height)
 }
 
+// triangleDamage returns the pixel-space bounding box of the triangle
+// within a width x height viewport. The triangle never moves, so swapping
+// only this region (instead of the whole surface) is enough to keep it on
+// screen.
+func triangleDamage(width, height int) image.Rectangle {
+	toPixel := func(ndc float32, extent int) int {
+		return int((ndc + 1) / 2 * float32(extent))
+	}
+	minX, maxX := toPixel(-0.5, width), toPixel(0.5, width)
+	minY, maxY := toPixel(-0.5, height), toPixel(0.5, height)
+	return image.Rect(minX, minY, maxX, maxY)
+}
+
 func printInfo() {
 	log.Printf("GL_RENDERER   = %s\n", gl.GetString(gl.RENDERER))
 	log.Printf("GL_VERSION    = %s\n", gl.GetString(gl.VERSION))
@@ -155,10 +239,18 @@ func printInfo() {
 
 func main() {
 	info := flag.Bool("info", false, "display OpenGL renderer info")
+	tracePath := flag.String("trace", "", "capture a GL trace to this .gfxtrace file")
 	flag.Parse()
 	if *info {
 		printInfo()
 	}
+	if *tracePath != "" {
+		var err error
+		tracer, err = trace.New(*tracePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	application.Register("render loop", newRenderLoop(FRAMES_PER_SECOND))
 	application.InstallSignalHandler(&signal)
 	exitCh := make(chan bool, 1)
@@ -0,0 +1,42 @@
+// Package glutil provides a few high-level conveniences over the es2
+// package for setup code every example ends up writing: uploading a typed
+// slice into a buffer without hand-computing its byte length. It's modeled
+// on golang.org/x/mobile/exp/gl/glutil.
+package glutil
+
+import (
+	"fmt"
+	"reflect"
+
+	gl "github.com/mortdeus/egles/es2"
+)
+
+// SliceBytes returns a pointer to data's backing array (a slice such as
+// []float32 or []uint16) and its length in bytes, for passing to
+// gl.BufferData, or anything wrapping it, without hand-computing the byte
+// length. It returns a nil pointer and zero size for an empty slice.
+func SliceBytes(data interface{}) (ptr gl.Void, size int) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("glutil: SliceBytes needs a slice, got %T", data))
+	}
+	if n := v.Len(); n > 0 {
+		return gl.Void(v.Pointer()), n * int(v.Type().Elem().Size())
+	}
+	return nil, 0
+}
+
+// CreateBuffer generates a buffer, binds it to target, and uploads data
+// (a slice such as []float32 or []uint16) to it with the given usage hint.
+// It returns the new buffer's handle.
+func CreateBuffer(target uint32, data interface{}, usage uint32) uint {
+	ptr, size := SliceBytes(data)
+
+	var buf uint
+	gl.GenBuffers(1, gl.Void(&buf))
+	gl.BindBuffer(target, buf)
+	if size > 0 {
+		gl.BufferData(target, size, ptr, usage)
+	}
+	return buf
+}
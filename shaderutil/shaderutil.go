@@ -0,0 +1,91 @@
+// Package shaderutil compiles and links OpenGL ES 2 shaders, surfacing
+// compiler and linker diagnostics as Go errors instead of discarding them.
+package shaderutil
+
+import (
+	"fmt"
+
+	gl "github.com/mortdeus/egles/es2"
+)
+
+// FragmentShader compiles s as a fragment shader and returns its handle.
+// If compilation fails, the returned error wraps the driver's info log.
+func FragmentShader(s string) (uint32, error) {
+	return compileShader(gl.FRAGMENT_SHADER, s)
+}
+
+// VertexShader compiles s as a vertex shader and returns its handle.
+// If compilation fails, the returned error wraps the driver's info log.
+func VertexShader(s string) (uint32, error) {
+	return compileShader(gl.VERTEX_SHADER, s)
+}
+
+func compileShader(kind uint32, src string) (uint32, error) {
+	shader := gl.CreateShader(kind)
+	gl.ShaderSource(shader, 1, &src, nil)
+	gl.CompileShader(shader)
+
+	var stat int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &stat)
+	if stat == 0 {
+		return 0, fmt.Errorf("shaderutil: compile shader: %s", shaderInfoLog(shader))
+	}
+	return shader, nil
+}
+
+// Program links fsh and vsh into a program object. If linking fails, the
+// returned error wraps the driver's info log.
+func Program(fsh, vsh uint32) (uint32, error) {
+	return ProgramWithAttribs(fsh, vsh, nil)
+}
+
+// ProgramWithAttribs links fsh and vsh into a program object, binding each
+// attrib location before linking so attribute locations are stable across
+// drivers instead of being driver-assigned (and possibly optimized away
+// for attributes LinkProgram decides are unused).
+func ProgramWithAttribs(fsh, vsh uint32, attribs map[uint32]string) (uint32, error) {
+	p := gl.CreateProgram()
+	gl.AttachShader(p, fsh)
+	gl.AttachShader(p, vsh)
+	for index, name := range attribs {
+		gl.BindAttribLocation(p, index, name)
+	}
+	gl.LinkProgram(p)
+
+	var stat int32
+	gl.GetProgramiv(p, gl.LINK_STATUS, &stat)
+	if stat == 0 {
+		return 0, fmt.Errorf("shaderutil: link program: %s", programInfoLog(p))
+	}
+	return p, nil
+}
+
+// shaderInfoLog reads the full compile log for shader, sizing the buffer
+// from INFO_LOG_LENGTH rather than guessing a fixed capacity.
+func shaderInfoLog(shader uint32) string {
+	var logLength int32
+	gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+	if logLength == 0 {
+		return "unknown error"
+	}
+	log := make([]byte, logLength)
+	logStr := string(log)
+	var written gl.Sizei
+	gl.GetShaderInfoLog(shader, logLength, &written, &logStr)
+	return logStr
+}
+
+// programInfoLog reads the full link log for p, sizing the buffer from
+// INFO_LOG_LENGTH rather than guessing a fixed capacity.
+func programInfoLog(p uint32) string {
+	var logLength int32
+	gl.GetProgramiv(p, gl.INFO_LOG_LENGTH, &logLength)
+	if logLength == 0 {
+		return "unknown error"
+	}
+	log := make([]byte, logLength)
+	logStr := string(log)
+	var written gl.Sizei
+	gl.GetProgramInfoLog(p, logLength, &written, &logStr)
+	return logStr
+}